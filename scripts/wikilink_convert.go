@@ -1,13 +1,20 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"gopkg.in/yaml.v3"
@@ -15,15 +22,22 @@ import (
 
 // Config represents application configuration
 type Config struct {
-	DryRun      bool     `yaml:"dry_run"`
-	Verbose     bool     `yaml:"verbose"`
-	Workers     int      `yaml:"workers"`
-	Sources     []string `yaml:"sources"`
-	Extensions  []string `yaml:"extensions"`
-	ExcludeDirs []string `yaml:"exclude_dirs"`
+	DryRun          bool     `yaml:"dry_run"`
+	Verbose         bool     `yaml:"verbose"`
+	NoModules       bool     `yaml:"no_modules"`
+	Force           bool     `yaml:"force"`
+	Watch           bool     `yaml:"watch"`
+	Workers         int      `yaml:"workers"`
+	Sources         []string `yaml:"sources"`
+	Extensions      []string `yaml:"extensions"`
+	ExcludeDirs     []string `yaml:"exclude_dirs"`
+	ImageOutput     string   `yaml:"image_output"`
+	ImageExtensions []string `yaml:"image_extensions"`
 }
 
-// LinkIndex maps slugs to their URL paths
+// LinkIndex maps slugs and clean paths to the URL paths of the files that
+// produced them. It is mutated in place by watch mode, so all access goes
+// through indexLookup/indexAdd/indexRemoveURL, guarded by indexMu.
 type LinkIndex map[string][]string
 
 // Mount represents a Hugo mount configuration
@@ -32,9 +46,15 @@ type Mount struct {
 	Target string `yaml:"target"`
 }
 
+// Import represents an imported Hugo Module
+type Import struct {
+	Path string `yaml:"path"`
+}
+
 // Module represents Hugo module configuration
 type Module struct {
-	Mounts []Mount `yaml:"mounts"`
+	Mounts  []Mount  `yaml:"mounts"`
+	Imports []Import `yaml:"imports"`
 }
 
 // HugoConfig represents Hugo configuration structure
@@ -42,10 +62,74 @@ type HugoConfig struct {
 	Module Module `yaml:"module"`
 }
 
+// CacheEntry records everything needed to decide, on a later run, whether a
+// source file can be skipped without re-reading or rewriting it.
+type CacheEntry struct {
+	ModTime    time.Time `json:"mtime"`
+	Size       int64     `json:"size"`
+	SourceHash string    `json:"sha256"`
+	OutputHash string    `json:"outputHash"`
+	Deps       []string  `json:"deps"`
+}
+
+// Manifest is the persisted, path-keyed link cache.
+type Manifest map[string]CacheEntry
+
+// LinkKind distinguishes a page wikilink from an image embed, so
+// downstream consumers (the graph builder, the CI checker) don't need to
+// re-derive it from the surrounding regex.
+type LinkKind string
+
+const (
+	LinkKindPage  LinkKind = "page"
+	LinkKindImage LinkKind = "image"
+)
+
+// ResolveResult is the outcome of resolving one wikilink, shared by the
+// in-place rewriter, `check`, and `graph` so they agree on what counts as
+// broken or ambiguous.
+type ResolveResult struct {
+	URL        string
+	Candidates []string
+	Kind       LinkKind
+	Broken     bool
+}
+
+const (
+	// cacheDir mirrors Hugo's own resources/_gen layout so generated state
+	// lives alongside the rest of a project's build output.
+	cacheDir     = "resources/_gen/wikilinks"
+	manifestFile = "manifest.json"
+)
+
 var (
 	config      Config
 	linkCache   sync.Map
 	excludeDirs map[string]bool
+
+	// contentIndex is the shared LinkIndex, built once at startup and kept
+	// up to date in place by watch mode.
+	contentIndex = make(LinkIndex)
+	indexMu      sync.RWMutex
+
+	manifest   Manifest
+	manifestMu sync.RWMutex
+
+	// pathIndex maps a resolved URL path back to the source file it was
+	// built from, so processFile can record it as a dependency.
+	pathIndex   = make(map[string]string)
+	pathIndexMu sync.Mutex
+
+	// leafBundles holds the directories containing an index.md, i.e. Hugo
+	// leaf bundles, whose non-markdown siblings are Page Resources rather
+	// than loose files needing a site-relative URL.
+	leafBundles = make(map[string]bool)
+
+	// bundleAssets maps a directory to the image file names (by
+	// config.ImageExtensions) found in it, so image wikilinks can be recognised as bundle resources once the
+	// directory is known to be a leaf bundle.
+	bundleAssets   = make(map[string]map[string]bool)
+	bundleAssetsMu sync.Mutex
 )
 
 // Regular expressions compiled at startup for better performance
@@ -53,6 +137,7 @@ var (
 	wikiLinkRegex  *regexp.Regexp
 	imageRegex     *regexp.Regexp
 	multiDashRegex *regexp.Regexp
+	imageSizeRegex *regexp.Regexp
 )
 
 func init() {
@@ -60,14 +145,18 @@ func init() {
 	wikiLinkRegex = regexp.MustCompile(`\[\[([^[\]]+?)(?:#([^|\]]+))?(?:\|([^[\]]+))?\]\]`)
 	imageRegex = regexp.MustCompile(`!\[\[([^[\]]+?)(?:\|([^[\]]+))?\]\]`)
 	multiDashRegex = regexp.MustCompile(`-+`)
+	// Matches Obsidian's embed sizing suffix, e.g. "|200" or "|200x100".
+	imageSizeRegex = regexp.MustCompile(`^(\d+)(?:x(\d+))?$`)
 
 	// Default configuration
 	config = Config{
-		DryRun:      false,
-		Verbose:     true,
-		Workers:     runtime.NumCPU(),
-		Extensions:  []string{".md", ".markdown"},
-		ExcludeDirs: []string{".git", "node_modules", "vendor", ".obsidian"},
+		DryRun:          false,
+		Verbose:         true,
+		Workers:         runtime.NumCPU(),
+		Extensions:      []string{".md", ".markdown"},
+		ExcludeDirs:     []string{".git", "node_modules", "vendor", ".obsidian"},
+		ImageOutput:     "markdown",
+		ImageExtensions: []string{".png", ".jpg", ".jpeg", ".svg", ".webp", ".gif"},
 	}
 
 	// Initialize exclude directories map
@@ -77,21 +166,76 @@ func init() {
 	}
 }
 
+// main dispatches to a subcommand: "convert" (the default, run when no
+// subcommand is given) rewrites wikilinks in place; "check", "graph" and
+// "prune" are read-only tools built on the same index and cache.
 func main() {
-	// Load configuration from file or environment variables
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "convert":
+			runConvert(os.Args[2:])
+			return
+		case "prune":
+			runPrune()
+			return
+		case "check":
+			runCheck(os.Args[2:])
+			return
+		case "graph":
+			runGraph(os.Args[2:])
+			return
+		}
+	}
+
+	runConvert(os.Args[1:])
+}
+
+// setupProject loads configuration, resolves content sources (including
+// imported Hugo Modules unless noModules is set), and builds the shared
+// content index. Every subcommand starts from this same state.
+func setupProject(noModules bool) []string {
 	if err := loadConfig(); err != nil && config.Verbose {
 		fmt.Printf("⚠️ Using default configuration: %v\n", err)
 	}
+	if noModules {
+		config.NoModules = true
+	}
 
-	// Get content sources from Hugo configuration
 	sources := getHugoMountSources()
 	if len(sources) == 0 {
 		sources = []string{"content"}
 	}
 	config.Sources = sources
 
-	// Build index of all content files
-	index := buildIndex(sources)
+	buildIndex(sources)
+	return sources
+}
+
+// runConvert is the default subcommand: it rewrites wikilinks to markdown
+// links in place, using the persistent link cache to skip unchanged files.
+func runConvert(args []string) {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	noModules := fs.Bool("no-modules", false, "don't resolve content mounts from imported Hugo Modules")
+	force := fs.Bool("force", false, "bypass the link cache and reprocess every file")
+	watch := fs.Bool("watch", false, "keep running and re-resolve files as they change")
+	fs.Parse(args)
+	if *force {
+		config.Force = true
+	}
+	if *watch {
+		config.Watch = true
+	}
+
+	sources := setupProject(*noModules)
+
+	var err error
+	manifest, err = loadManifest()
+	if err != nil {
+		manifest = make(Manifest)
+		if config.Verbose {
+			fmt.Printf("⚠️ Starting with an empty link cache: %v\n", err)
+		}
+	}
 
 	// Process files
 	fileChan := make(chan string, 100)
@@ -103,9 +247,17 @@ func main() {
 		go func(workerID int) {
 			defer wg.Done()
 			for path := range fileChan {
-				if err := processFile(path, index, sources); err != nil {
+				entry, err := processFile(path, sources)
+				if err != nil {
 					fmt.Printf("❌ Worker %d: %s: %v\n", workerID, path, err)
-				} else if config.Verbose {
+					continue
+				}
+
+				manifestMu.Lock()
+				manifest[path] = entry
+				manifestMu.Unlock()
+
+				if config.Verbose {
 					fmt.Printf("✅ Worker %d: %s processed\n", workerID, path)
 				}
 			}
@@ -114,7 +266,7 @@ func main() {
 
 	// Walk through all source directories and send files to workers
 	for _, src := range sources {
-		err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		err := symbolicWalk(src, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return fmt.Errorf("access error: %w", err)
 			}
@@ -132,6 +284,13 @@ func main() {
 				return nil
 			}
 
+			if !config.Force && !needsProcessing(path, info) {
+				if config.Verbose {
+					fmt.Printf("⏭️  %s unchanged, skipping\n", path)
+				}
+				return nil
+			}
+
 			fileChan <- path
 			return nil
 		})
@@ -144,12 +303,131 @@ func main() {
 	close(fileChan)
 	wg.Wait()
 
+	if err := saveManifest(snapshotManifest()); err != nil && config.Verbose {
+		fmt.Printf("⚠️ Failed to save link cache: %v\n", err)
+	}
+
 	if config.Verbose {
 		fmt.Println("🚀 Processing completed successfully")
 		if config.DryRun {
 			fmt.Println("📝 Dry run mode - no files were modified")
 		}
 	}
+
+	if config.Watch {
+		if err := runWatch(sources); err != nil {
+			fmt.Printf("❌ Watch mode stopped: %v\n", err)
+		}
+	}
+}
+
+// needsProcessing reports whether path must be (re-)processed: it is
+// unknown to the cache, its (mtime, size) changed since it was last
+// recorded, or one of the wikilink targets it depends on did.
+func needsProcessing(path string, info os.FileInfo) bool {
+	manifestMu.RLock()
+	entry, ok := manifest[path]
+	manifestMu.RUnlock()
+	if !ok {
+		return true
+	}
+
+	if !info.ModTime().Equal(entry.ModTime) || info.Size() != entry.Size {
+		return true
+	}
+
+	return !depsUnchanged(entry.Deps)
+}
+
+// depsUnchanged reports whether every dependency still exists and, for
+// those also tracked in the manifest, still has its recorded mtime.
+func depsUnchanged(deps []string) bool {
+	for _, dep := range deps {
+		info, err := os.Stat(dep)
+		if err != nil {
+			return false
+		}
+
+		manifestMu.RLock()
+		depEntry, ok := manifest[dep]
+		manifestMu.RUnlock()
+		if ok && !info.ModTime().Equal(depEntry.ModTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// loadManifest reads the persisted link cache from cacheDir.
+func loadManifest() (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(Manifest)
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse link cache: %w", err)
+	}
+
+	return m, nil
+}
+
+// snapshotManifest returns a shallow copy of the shared manifest, taken
+// under manifestMu, so callers can marshal or inspect it without racing
+// against concurrent writers.
+func snapshotManifest() Manifest {
+	manifestMu.RLock()
+	defer manifestMu.RUnlock()
+
+	m := make(Manifest, len(manifest))
+	for k, v := range manifest {
+		m[k] = v
+	}
+	return m
+}
+
+// saveManifest persists the link cache to cacheDir, creating it if needed.
+func saveManifest(m Manifest) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode link cache: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cacheDir, manifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write link cache: %w", err)
+	}
+
+	return nil
+}
+
+// runPrune drops manifest entries for files that no longer exist on disk.
+func runPrune() {
+	m, err := loadManifest()
+	if err != nil {
+		fmt.Printf("⚠️ Nothing to prune: %v\n", err)
+		return
+	}
+
+	pruned := 0
+	for path := range m {
+		if _, err := os.Stat(path); err != nil {
+			delete(m, path)
+			pruned++
+		}
+	}
+
+	if err := saveManifest(m); err != nil {
+		fmt.Printf("❌ Failed to save pruned link cache: %v\n", err)
+		return
+	}
+
+	fmt.Printf("🧹 Pruned %d stale entries from the link cache\n", pruned)
 }
 
 // loadConfig loads configuration from file or environment variables
@@ -185,56 +463,219 @@ func loadConfig() error {
 	return fmt.Errorf("no configuration file found, using defaults")
 }
 
-// getHugoMountSources retrieves content sources from Hugo configuration
+// hugoConfigCandidates are the well-known locations of a Hugo project's
+// (or module's) root configuration file, checked in order.
+var hugoConfigCandidates = []string{
+	"hugo.yaml",
+	"hugo.yml",
+	"config.yaml",
+	"config.yml",
+	"config/_default/hugo.yaml",
+	"config/_default/hugo.yml",
+	"config/_default/config.yaml",
+	"config/_default/config.yml",
+}
+
+// readHugoConfig loads the Hugo configuration rooted at dir, trying each of
+// hugoConfigCandidates in turn and returning the first one that parses.
+func readHugoConfig(dir string) (HugoConfig, bool) {
+	for _, f := range hugoConfigCandidates {
+		path := filepath.Join(dir, f)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var cfg HugoConfig
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			continue
+		}
+
+		return cfg, true
+	}
+
+	return HugoConfig{}, false
+}
+
+// getHugoMountSources retrieves content sources from Hugo configuration,
+// including any contributed by imported Hugo Modules.
 func getHugoMountSources() []string {
-	candidates := []string{
-		"hugo.yaml",
-		"hugo.yml",
-		"config.yaml",
-		"config.yml",
-		"config/_default/hugo.yaml",
-		"config/_default/hugo.yml",
-		"config/_default/config.yaml",
-		"config/_default/config.yml",
-	}
-
-	var mounts []Mount
-	for _, f := range candidates {
-		if _, err := os.Stat(f); err == nil {
-			data, err := os.ReadFile(f)
-			if err != nil {
-				continue
-			}
+	cfg, ok := readHugoConfig(".")
+	if !ok {
+		return nil
+	}
 
-			var cfg HugoConfig
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				continue
-			}
+	var sources []string
+	for _, m := range cfg.Module.Mounts {
+		if m.Target == "content" {
+			sources = append(sources, m.Source)
+		}
+	}
+	if len(sources) == 0 {
+		// No explicit content mount: Hugo falls back to the project's own
+		// content directory, and imported modules only ever add to that,
+		// never replace it.
+		sources = []string{"content"}
+	}
 
-			for _, m := range cfg.Module.Mounts {
-				if m.Target == "content" {
-					mounts = append(mounts, m)
-				}
+	if !config.NoModules {
+		sources = append(sources, getModuleImportSources(cfg.Module.Imports)...)
+	}
+
+	return sources
+}
+
+// getModuleImportSources resolves each imported Hugo Module's own content
+// mounts into source directories, so wikilinks can target files shipped by
+// an imported theme or component rather than just the local project.
+func getModuleImportSources(imports []Import) []string {
+	var sources []string
+	for _, imp := range imports {
+		dir, ok := resolveModuleDir(imp.Path)
+		if !ok {
+			if config.Verbose {
+				fmt.Printf("⚠️ Could not locate imported module %s\n", imp.Path)
 			}
+			continue
+		}
 
-			if len(mounts) > 0 {
-				break
+		cfg, ok := readHugoConfig(dir)
+		if !ok {
+			continue
+		}
+
+		for _, m := range cfg.Module.Mounts {
+			if m.Target == "content" {
+				sources = append(sources, filepath.Join(dir, m.Source))
 			}
 		}
 	}
 
-	var sources []string
-	for _, m := range mounts {
-		sources = append(sources, m.Source)
+	return sources
+}
+
+// resolveModuleDir locates the on-disk directory for an imported module
+// path, checking the project's own _vendor directory first and then the
+// Hugo/Go module cache.
+func resolveModuleDir(importPath string) (string, bool) {
+	vendored := filepath.Join("_vendor", filepath.FromSlash(importPath))
+	if info, err := os.Stat(vendored); err == nil && info.IsDir() {
+		return vendored, true
 	}
 
-	return sources
+	cached := filepath.Join(hugoModuleCacheDir(), filepath.FromSlash(escapeModulePath(importPath)))
+	if matches, err := filepath.Glob(cached + "@*"); err == nil && len(matches) > 0 {
+		// Module cache directories are suffixed with @<version>; pick the
+		// highest by semver order, not lexicographic order, since e.g.
+		// "@v1.2.0" sorts after "@v1.10.0" as plain strings.
+		sort.Slice(matches, func(i, j int) bool {
+			return semverLess(moduleCacheVersion(matches[i]), moduleCacheVersion(matches[j]))
+		})
+		return matches[len(matches)-1], true
+	}
+
+	if info, err := os.Stat(cached); err == nil && info.IsDir() {
+		return cached, true
+	}
+
+	return "", false
+}
+
+// escapeModulePath applies Go's module cache escaping (cf.
+// golang.org/x/mod/module.EscapePath): since module cache directories live
+// on case-insensitive filesystems too, every uppercase letter is replaced
+// with "!" followed by its lowercase form, e.g. "github.com/Azure/x"
+// becomes "github.com/!azure/x".
+func escapeModulePath(path string) string {
+	var buf strings.Builder
+	for _, r := range path {
+		if r >= 'A' && r <= 'Z' {
+			buf.WriteByte('!')
+			buf.WriteRune(r - 'A' + 'a')
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// moduleCacheVersion extracts the "@<version>" suffix from a module cache
+// directory path, e.g. ".../pkg/mod/example.com/theme@v1.10.0" -> "v1.10.0".
+func moduleCacheVersion(dir string) string {
+	if i := strings.LastIndex(dir, "@"); i != -1 {
+		return dir[i+1:]
+	}
+	return ""
+}
+
+// semverLess reports whether version a sorts before version b, comparing
+// the dot-separated numeric release components (major.minor.patch, ignoring
+// any "v" prefix or "-pre"/"+build" suffix) numerically rather than
+// lexicographically, so "v1.2.0" correctly sorts before "v1.10.0". Versions
+// that don't parse as numeric components fall back to a plain string
+// comparison.
+func semverLess(a, b string) bool {
+	pa, okA := parseSemverCore(a)
+	pb, okB := parseSemverCore(b)
+	if !okA || !okB {
+		return a < b
+	}
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] != pb[i] {
+			return pa[i] < pb[i]
+		}
+	}
+	return false
 }
 
-// buildIndex creates an index of all content files for efficient lookup
-func buildIndex(sources []string) LinkIndex {
-	index := make(LinkIndex)
-	var mu sync.Mutex
+// parseSemverCore parses the major.minor.patch numeric components out of a
+// version string such as "v1.10.0-beta.1+meta", ignoring any leading "v" and
+// any pre-release/build metadata suffix.
+func parseSemverCore(v string) ([3]int, bool) {
+	var core [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i != -1 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return core, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return core, false
+		}
+		core[i] = n
+	}
+	return core, true
+}
+
+// hugoModuleCacheDir returns the root of the Hugo Modules cache, honouring
+// HUGO_CACHEDIR and HUGO_MODULE_WORKSPACE before falling back to the
+// standard Go module cache under GOPATH.
+func hugoModuleCacheDir() string {
+	if dir := os.Getenv("HUGO_CACHEDIR"); dir != "" {
+		return filepath.Join(dir, "modules", "filecache", "modules", "pkg", "mod")
+	}
+
+	if ws := os.Getenv("HUGO_MODULE_WORKSPACE"); ws != "" {
+		return filepath.Join(ws, "pkg", "mod")
+	}
+
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+
+	return filepath.Join(gopath, "pkg", "mod")
+}
+
+// buildIndex walks sources and populates the global contentIndex for
+// efficient lookup.
+func buildIndex(sources []string) {
 	var wg sync.WaitGroup
 
 	for _, src := range sources {
@@ -242,7 +683,7 @@ func buildIndex(sources []string) LinkIndex {
 		go func(source string) {
 			defer wg.Done()
 
-			err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+			err := symbolicWalk(source, func(path string, info os.FileInfo, err error) error {
 				if err != nil {
 					return fmt.Errorf("access error: %w", err)
 				}
@@ -255,29 +696,23 @@ func buildIndex(sources []string) LinkIndex {
 					return nil
 				}
 
+				dir := filepath.Dir(path)
+
+				if info.Name() == "index.md" {
+					markLeafBundle(dir)
+				} else if hasValidExtension(info.Name(), config.ImageExtensions) {
+					recordBundleAsset(dir, info.Name())
+				}
+
 				// Check if file has a valid extension
 				if !hasValidExtension(info.Name(), config.Extensions) {
 					return nil
 				}
 
-				rel, err := filepath.Rel(source, path)
-				if err != nil {
-					return fmt.Errorf("failed to get relative path: %w", err)
-				}
-
-				urlPath := "/" + strings.TrimSuffix(filepath.ToSlash(rel), ".md")
-				slug := slugify(strings.TrimSuffix(info.Name(), ".md"))
-				cleanPath := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
-
-				mu.Lock()
-				index[slug] = append(index[slug], urlPath)
-				index[cleanPath] = append(index[cleanPath], urlPath)
-
-				if strings.HasPrefix(cleanPath, "/") {
-					index[cleanPath[1:]] = append(index[cleanPath[1:]], urlPath)
+				indexFile(source, path)
+				if isSymlink(path) {
+					indexSymlinkTarget(source, path)
 				}
-				mu.Unlock()
-
 				return nil
 			})
 
@@ -288,27 +723,225 @@ func buildIndex(sources []string) LinkIndex {
 	}
 
 	wg.Wait()
-	return index
 }
 
-// processFile processes a single file, converting wikilinks to markdown links
-func processFile(path string, index LinkIndex, sources []string) error {
-	content, err := os.ReadFile(path)
+// isSymlink reports whether path itself (not what it points to) is a
+// symbolic link.
+func isSymlink(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
+
+// indexSymlinkTarget additionally indexes a symlinked file under its
+// target's slug, so a wikilink written against either the link's own name
+// or the name of the file it points to resolves to the same page.
+func indexSymlinkTarget(source, path string) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil || filepath.Base(target) == filepath.Base(path) {
+		return
+	}
+
+	urlPath, _, _, err := indexKeysFor(source, path)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return
 	}
 
-	original := string(content)
+	targetSlug := slugify(strings.TrimSuffix(filepath.Base(target), ".md"))
+	indexAdd(targetSlug, urlPath)
+}
 
-	// Determine content directory and relative path
-	var contentDir string
+// symbolicWalk is filepath.Walk with symlink support: Hugo permits symlinks
+// anywhere under content, for both files and directories, so unlike
+// filepath.Walk this follows directory symlinks rather than reporting them
+// as plain (non-recursed) entries. Cycles are guarded against by tracking
+// each directory's resolved real path in visited; dangling symlinks and
+// cycle breaks are logged under Verbose.
+func symbolicWalk(root string, walkFn filepath.WalkFunc) error {
+	return symbolicWalkPath(root, make(map[string]bool), walkFn)
+}
+
+func symbolicWalkPath(path string, visited map[string]bool, walkFn filepath.WalkFunc) error {
+	lst, err := os.Lstat(path)
+	if err != nil {
+		return walkFn(path, nil, err)
+	}
+
+	info := lst
+	if lst.Mode()&os.ModeSymlink != 0 {
+		resolved, statErr := os.Stat(path)
+		if statErr != nil {
+			if config.Verbose {
+				fmt.Printf("⚠️ Skipping dangling symlink %s\n", path)
+			}
+			return nil
+		}
+		info = resolved
+	}
+
+	if info.IsDir() {
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			real = path
+		}
+		if abs, err := filepath.Abs(real); err == nil {
+			real = abs
+		}
+		if visited[real] {
+			if config.Verbose {
+				fmt.Printf("⚠️ Breaking symlink cycle at %s\n", path)
+			}
+			return nil
+		}
+		visited[real] = true
+	}
+
+	if err := walkFn(path, info, nil); err != nil {
+		if err == filepath.SkipDir {
+			return nil
+		}
+		return err
+	}
+
+	if !info.IsDir() {
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return walkFn(path, info, err)
+	}
+
+	for _, entry := range entries {
+		if err := symbolicWalkPath(filepath.Join(path, entry.Name()), visited, walkFn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexKeysFor computes the URL path, slug, and clean path a source/path
+// pair maps to, without touching the filesystem.
+func indexKeysFor(source, path string) (urlPath, slug, cleanPath string, err error) {
+	rel, err := filepath.Rel(source, path)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get relative path: %w", err)
+	}
+
+	urlPath = "/" + strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+	slug = slugify(strings.TrimSuffix(filepath.Base(path), ".md"))
+	cleanPath = strings.ToLower(strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
+	return urlPath, slug, cleanPath, nil
+}
+
+// indexFile adds path (found under source) to contentIndex and pathIndex.
+func indexFile(source, path string) error {
+	urlPath, slug, cleanPath, err := indexKeysFor(source, path)
+	if err != nil {
+		return err
+	}
+
+	indexAdd(slug, urlPath)
+	indexAdd(cleanPath, urlPath)
+	if strings.HasPrefix(cleanPath, "/") {
+		indexAdd(cleanPath[1:], urlPath)
+	}
+
+	pathIndexMu.Lock()
+	pathIndex[urlPath] = path
+	pathIndexMu.Unlock()
+
+	return nil
+}
+
+// deindexFile removes path (found under source) from contentIndex and
+// pathIndex. It does not touch linkCache; callers invalidate that
+// separately.
+func deindexFile(source, path string) error {
+	urlPath, slug, cleanPath, err := indexKeysFor(source, path)
+	if err != nil {
+		return err
+	}
+
+	indexRemoveURL(slug, urlPath)
+	indexRemoveURL(cleanPath, urlPath)
+	if strings.HasPrefix(cleanPath, "/") {
+		indexRemoveURL(cleanPath[1:], urlPath)
+	}
+
+	pathIndexMu.Lock()
+	delete(pathIndex, urlPath)
+	pathIndexMu.Unlock()
+
+	return nil
+}
+
+// sourceForPath returns the source directory path was found under.
+func sourceForPath(path string, sources []string) (string, bool) {
 	for _, src := range sources {
 		if strings.HasPrefix(filepath.ToSlash(path), filepath.ToSlash(src)) {
-			contentDir = src
+			return src, true
+		}
+	}
+	return "", false
+}
+
+// indexLookup returns the URL paths contentIndex has recorded for key.
+func indexLookup(key string) ([]string, bool) {
+	indexMu.RLock()
+	defer indexMu.RUnlock()
+	candidates, ok := contentIndex[key]
+	return candidates, ok
+}
+
+// indexAdd records that key resolves to urlPath.
+func indexAdd(key, urlPath string) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	contentIndex[key] = append(contentIndex[key], urlPath)
+}
+
+// indexRemoveURL drops urlPath from key's candidates, removing the key
+// entirely once it has none left.
+func indexRemoveURL(key, urlPath string) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+
+	candidates := contentIndex[key]
+	for i, c := range candidates {
+		if c == urlPath {
+			candidates = append(candidates[:i], candidates[i+1:]...)
 			break
 		}
 	}
 
+	if len(candidates) == 0 {
+		delete(contentIndex, key)
+	} else {
+		contentIndex[key] = candidates
+	}
+}
+
+// processFile processes a single file, converting wikilinks to markdown
+// links, and returns the cache entry recording the work done so a later run
+// can decide whether the file needs to be revisited.
+func processFile(path string, sources []string) (CacheEntry, error) {
+	origInfo, err := os.Stat(path)
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return CacheEntry{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	original := string(content)
+	var deps []string
+
+	// Determine content directory and relative path
+	contentDir, _ := sourceForPath(path, sources)
+
 	currentDir := filepath.Dir(path)
 	relCurrentDir, _ := filepath.Rel(contentDir, currentDir)
 
@@ -325,14 +958,26 @@ func processFile(path string, index LinkIndex, sources []string) error {
 			}
 		}
 
-		// Check for alias (|Title)
+		// Check for alias (|Title) or Obsidian's |WIDTHxHEIGHT size suffix
+		width, height := "", ""
 		if sub[2] != "" {
-			displayText = sub[2]
+			if m := imageSizeRegex.FindStringSubmatch(sub[2]); m != nil {
+				width, height = m[1], m[2]
+			} else {
+				displayText = sub[2]
+			}
+		}
+
+		if isBundleAsset(currentDir, filename) {
+			return bundleImage(filename, displayText, width, height)
 		}
 
-		url := resolveLink(filename, index, path, relCurrentDir)
+		res := resolveLink(filename, LinkKindImage, path, relCurrentDir)
+		url := res.URL
 		if url == "" {
 			url = filename
+		} else if depPath, ok := pathIndexLookup(url); ok {
+			deps = append(deps, depPath)
 		}
 
 		return fmt.Sprintf(`![%s](%s)`, displayText, url)
@@ -351,109 +996,199 @@ func processFile(path string, index LinkIndex, sources []string) error {
 			displayText = sub[3]
 		}
 
-		url := resolveLink(linkName, index, path, relCurrentDir)
-		if url == "" {
+		res := resolveLink(linkName, LinkKindPage, path, relCurrentDir)
+		if res.Broken {
 			if config.Verbose {
 				fmt.Printf("⚠️ Broken link detected: '%s' in file %s\n", linkName, path)
 			}
 			return fmt.Sprintf(`<span class="broken-link">%s</span>`, displayText)
 		}
-		return fmt.Sprintf("[%s](%s%s)", displayText, url, fragment)
+		if depPath, ok := pathIndexLookup(res.URL); ok {
+			deps = append(deps, depPath)
+		}
+		return fmt.Sprintf("[%s](%s%s)", displayText, res.URL, fragment)
 	})
 
+	sourceHash := sha256Hex(content)
+	outputHash := sourceHash
+
 	// Write changes if not in dry run mode and content has changed
 	if !config.DryRun && newContent != original {
 		if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
+			return CacheEntry{}, fmt.Errorf("failed to write file: %w", err)
+		}
+		outputHash = sha256Hex([]byte(newContent))
+
+		if info, err := os.Stat(path); err == nil {
+			origInfo = info
 		}
 	}
 
-	return nil
+	return CacheEntry{
+		ModTime:    origInfo.ModTime(),
+		Size:       origInfo.Size(),
+		SourceHash: sourceHash,
+		OutputHash: outputHash,
+		Deps:       deps,
+	}, nil
+}
+
+// markLeafBundle records dir as a Hugo leaf bundle (a directory whose
+// index.md makes its siblings Page Resources rather than loose files).
+func markLeafBundle(dir string) {
+	bundleAssetsMu.Lock()
+	defer bundleAssetsMu.Unlock()
+	leafBundles[dir] = true
 }
 
-// resolveLink finds the best matching URL for a given wikilink
-func resolveLink(linkName string, index LinkIndex, currentFile, relCurrentDir string) string {
+// recordBundleAsset records name as an image file found in dir, to be
+// resolved against leafBundles once the whole tree has been walked.
+func recordBundleAsset(dir, name string) {
+	bundleAssetsMu.Lock()
+	defer bundleAssetsMu.Unlock()
+	if bundleAssets[dir] == nil {
+		bundleAssets[dir] = make(map[string]bool)
+	}
+	bundleAssets[dir][name] = true
+}
+
+// isBundleAsset reports whether name is a Page Resource of the leaf bundle
+// at dir, i.e. dir has an index.md and name was found alongside it.
+func isBundleAsset(dir, name string) bool {
+	bundleAssetsMu.Lock()
+	defer bundleAssetsMu.Unlock()
+	return leafBundles[dir] && bundleAssets[dir][name]
+}
+
+// bundleImage renders a Page Resource image inside a leaf bundle, in
+// whichever form config.ImageOutput selects.
+func bundleImage(filename, alt, width, height string) string {
+	switch config.ImageOutput {
+	case "figure":
+		attrs := fmt.Sprintf(`src="%s" alt="%s"`, filename, alt)
+		if width != "" {
+			attrs += fmt.Sprintf(` width="%s"`, width)
+		}
+		if height != "" {
+			attrs += fmt.Sprintf(` height="%s"`, height)
+		}
+		return fmt.Sprintf("{{< figure %s >}}", attrs)
+	case "resource":
+		return fmt.Sprintf("![%s](./%s)", alt, filename)
+	default: // "markdown"
+		return fmt.Sprintf("![%s](%s)", alt, filename)
+	}
+}
+
+// pathIndexLookup returns the source file a resolved URL path was built
+// from, if it is a local file tracked in the content index.
+func pathIndexLookup(urlPath string) (string, bool) {
+	pathIndexMu.Lock()
+	defer pathIndexMu.Unlock()
+	path, ok := pathIndex[urlPath]
+	return path, ok
+}
+
+// sha256Hex returns the hex-encoded SHA-256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// resolveLink finds the best matching URL for a given wikilink, returning a
+// ResolveResult so callers that just need the URL (the rewriter) and
+// callers that need the full picture (`check`, `graph`) share one path.
+func resolveLink(linkName string, kind LinkKind, currentFile, relCurrentDir string) ResolveResult {
 	cacheKey := linkName + "|" + currentFile
 	if cached, found := linkCache.Load(cacheKey); found {
-		return cached.(string)
+		return cached.(ResolveResult)
 	}
 
 	var url string
+	var candidates []string
 	lowerLink := strings.ToLower(linkName)
 
 	// Handle absolute paths
 	if strings.HasPrefix(linkName, "/") {
 		clean := strings.TrimPrefix(filepath.ToSlash(lowerLink), "/")
 		clean = strings.TrimSuffix(clean, ".md")
-		url = findBestMatch(clean, index, currentFile)
+		url, candidates = findBestMatch(clean, currentFile)
 	} else if strings.HasPrefix(linkName, "../") || strings.HasPrefix(linkName, "./") {
 		// Handle relative paths
 		absPath := filepath.Clean(filepath.Join(filepath.Dir(currentFile), linkName))
 		cleanPath := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(absPath), ".md"))
-		if candidates, ok := index[cleanPath]; ok && len(candidates) > 0 {
-			url = candidates[0]
+		if c, ok := indexLookup(cleanPath); ok && len(c) > 0 {
+			url, candidates = c[0], c
 		}
 	} else if strings.Contains(linkName, "/") {
 		// Handle paths with directories
 		fullPath := filepath.Join(relCurrentDir, linkName)
 		cleanPath := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(fullPath), ".md"))
-		if candidates, ok := index[cleanPath]; ok && len(candidates) > 0 {
-			url = candidates[0]
+		if c, ok := indexLookup(cleanPath); ok && len(c) > 0 {
+			url, candidates = c[0], c
 		}
 	}
 
 	// Fallback to slug matching
 	if url == "" {
 		slug := slugify(linkName)
-		if candidates, ok := index[slug]; ok && len(candidates) > 0 {
-			url = candidates[0]
-			if len(candidates) > 1 && config.Verbose {
+		if c, ok := indexLookup(slug); ok && len(c) > 0 {
+			url, candidates = c[0], c
+			if len(c) > 1 && config.Verbose {
 				fmt.Printf("⚠️ Ambiguous link '%s' in %s → picked %s (candidates: %v)\n",
-					linkName, currentFile, url, candidates)
+					linkName, currentFile, url, c)
 			}
 		}
 	}
 
+	result := ResolveResult{
+		URL:        url,
+		Candidates: candidates,
+		Kind:       kind,
+		Broken:     url == "",
+	}
+
 	// Cache the result
-	linkCache.Store(cacheKey, url)
-	return url
+	linkCache.Store(cacheKey, result)
+	return result
 }
 
-// findBestMatch finds the best URL match for a given key
-func findBestMatch(key string, index LinkIndex, currentFile string) string {
-	if candidates, ok := index[key]; ok && len(candidates) > 0 {
-		return candidates[0]
+// findBestMatch finds the best URL match (and its full candidate list) for
+// a given key.
+func findBestMatch(key string, currentFile string) (string, []string) {
+	if candidates, ok := indexLookup(key); ok && len(candidates) > 0 {
+		return candidates[0], candidates
 	}
 
 	parts := strings.Split(key, "/")
 	if len(parts) > 1 {
 		last := parts[len(parts)-1]
-		if candidates, ok := index[last]; ok && len(candidates) > 0 {
-			return candidates[0]
+		if candidates, ok := indexLookup(last); ok && len(candidates) > 0 {
+			return candidates[0], candidates
 		}
 	}
 
 	slug := slugify(key)
-	if candidates, ok := index[slug]; ok && len(candidates) > 0 {
+	if candidates, ok := indexLookup(slug); ok && len(candidates) > 0 {
 		if len(candidates) > 1 && config.Verbose {
 			fmt.Printf("⚠️ Ambiguous link '%s' in %s → picked %s (candidates: %v)\n",
 				key, currentFile, candidates[0], candidates)
 		}
-		return candidates[0]
+		return candidates[0], candidates
 	}
 
 	base := filepath.Base(key)
 	if base != key {
-		if candidates, ok := index[base]; ok && len(candidates) > 0 {
-			return candidates[0]
+		if candidates, ok := indexLookup(base); ok && len(candidates) > 0 {
+			return candidates[0], candidates
 		}
 		slugBase := slugify(base)
-		if candidates, ok := index[slugBase]; ok && len(candidates) > 0 {
-			return candidates[0]
+		if candidates, ok := indexLookup(slugBase); ok && len(candidates) > 0 {
+			return candidates[0], candidates
 		}
 	}
 
-	return ""
+	return "", nil
 }
 
 // slugify converts a string to a URL-friendly slug
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LinkOccurrence is one wikilink found while scanning a file, paired with
+// how it resolved. check and graph both walk the same occurrences; check
+// cares about Result.Broken/ambiguous, graph just wants the edges.
+type LinkOccurrence struct {
+	File   string
+	Link   string
+	Result ResolveResult
+}
+
+// scanFile finds every wikilink and image embed in path and resolves each
+// one, without writing anything back. It is the read-only counterpart to
+// processFile, shared by runCheck and runGraph so they never disagree with
+// the rewriter about what a link resolves to.
+func scanFile(path string, sources []string) ([]LinkOccurrence, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	contentDir, _ := sourceForPath(path, sources)
+	currentDir := filepath.Dir(path)
+	relCurrentDir, _ := filepath.Rel(contentDir, currentDir)
+
+	var occurrences []LinkOccurrence
+
+	for _, sub := range imageRegex.FindAllStringSubmatch(string(content), -1) {
+		filename := strings.TrimSpace(sub[1])
+		if isBundleAsset(currentDir, filename) {
+			continue
+		}
+		res := resolveLink(filename, LinkKindImage, path, relCurrentDir)
+		occurrences = append(occurrences, LinkOccurrence{File: path, Link: filename, Result: res})
+	}
+
+	// Like processFile, look for page wikilinks only once image embeds are
+	// out of the way, so "![[x]]" is not also counted as a page link.
+	withoutImages := imageRegex.ReplaceAllString(string(content), "")
+	for _, sub := range wikiLinkRegex.FindAllStringSubmatch(withoutImages, -1) {
+		linkName := strings.TrimSpace(sub[1])
+		res := resolveLink(linkName, LinkKindPage, path, relCurrentDir)
+		occurrences = append(occurrences, LinkOccurrence{File: path, Link: linkName, Result: res})
+	}
+
+	return occurrences, nil
+}
+
+// walkSources calls fn for every file under sources with a valid extension.
+func walkSources(sources []string, fn func(path string)) {
+	for _, src := range sources {
+		symbolicWalk(src, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				if excludeDirs[info.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !hasValidExtension(info.Name(), config.Extensions) {
+				return nil
+			}
+			fn(path)
+			return nil
+		})
+	}
+}
+
+// checkIssue is one broken or ambiguous link, in the shape --format=json
+// reports it for CI consumption.
+type checkIssue struct {
+	File       string   `json:"file"`
+	Link       string   `json:"link"`
+	Reason     string   `json:"reason"`
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// runCheck dry-run resolves every wikilink under the content sources and
+// reports broken and ambiguous links, exiting non-zero if it finds any —
+// intended for CI.
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	noModules := fs.Bool("no-modules", false, "don't resolve content mounts from imported Hugo Modules")
+	format := fs.String("format", "text", "output format: text or json")
+	fs.Parse(args)
+
+	sources := setupProject(*noModules)
+
+	var issues []checkIssue
+	walkSources(sources, func(path string) {
+		occurrences, err := scanFile(path, sources)
+		if err != nil {
+			if config.Verbose {
+				fmt.Printf("⚠️ Error scanning %s: %v\n", path, err)
+			}
+			return
+		}
+		for _, occ := range occurrences {
+			switch {
+			case occ.Result.Broken:
+				issues = append(issues, checkIssue{File: occ.File, Link: occ.Link, Reason: "broken"})
+			case len(occ.Result.Candidates) > 1:
+				issues = append(issues, checkIssue{
+					File: occ.File, Link: occ.Link, Reason: "ambiguous", Candidates: occ.Result.Candidates,
+				})
+			}
+		}
+	})
+
+	if *format == "json" {
+		data, _ := json.MarshalIndent(issues, "", "  ")
+		fmt.Println(string(data))
+	} else if len(issues) == 0 {
+		fmt.Println("✅ No broken or ambiguous links found")
+	} else {
+		for _, issue := range issues {
+			fmt.Printf("❌ %s: '%s' is %s", issue.File, issue.Link, issue.Reason)
+			if len(issue.Candidates) > 0 {
+				fmt.Printf(" (candidates: %v)", issue.Candidates)
+			}
+			fmt.Println()
+		}
+	}
+
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// graphNode is one page in the link graph, identified by its resolved URL
+// path.
+type graphNode struct {
+	URL  string `json:"url"`
+	File string `json:"file"`
+}
+
+// graphEdge is one wikilink reference from a source file to a resolved URL
+// (or, for a broken link, to the literal link text).
+type graphEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Kind      string `json:"kind"`
+	Broken    bool   `json:"broken,omitempty"`
+	Ambiguous bool   `json:"ambiguous,omitempty"`
+}
+
+// runGraph emits the link graph — one node per content page, one edge per
+// wikilink reference — as Graphviz DOT (the default) or JSON.
+func runGraph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	noModules := fs.Bool("no-modules", false, "don't resolve content mounts from imported Hugo Modules")
+	format := fs.String("format", "dot", "output format: dot or json")
+	fs.Parse(args)
+
+	sources := setupProject(*noModules)
+
+	var nodes []graphNode
+	var edges []graphEdge
+
+	walkSources(sources, func(path string) {
+		source, ok := sourceForPath(path, sources)
+		if !ok {
+			return
+		}
+		urlPath, _, _, err := indexKeysFor(source, path)
+		if err != nil {
+			return
+		}
+		nodes = append(nodes, graphNode{URL: urlPath, File: path})
+
+		occurrences, err := scanFile(path, sources)
+		if err != nil {
+			return
+		}
+		for _, occ := range occurrences {
+			to := occ.Result.URL
+			if to == "" {
+				to = occ.Link
+			}
+			edges = append(edges, graphEdge{
+				From:      urlPath,
+				To:        to,
+				Kind:      string(occ.Result.Kind),
+				Broken:    occ.Result.Broken,
+				Ambiguous: len(occ.Result.Candidates) > 1,
+			})
+		}
+	})
+
+	if *format == "json" {
+		data, _ := json.MarshalIndent(struct {
+			Nodes []graphNode `json:"nodes"`
+			Edges []graphEdge `json:"edges"`
+		}{nodes, edges}, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Println("digraph wikilinks {")
+	for _, n := range nodes {
+		fmt.Printf("  %q;\n", n.URL)
+	}
+	for _, e := range edges {
+		attrs := fmt.Sprintf(`label=%q`, e.Kind)
+		if e.Broken {
+			attrs += ` color=red style=dashed`
+		} else if e.Ambiguous {
+			attrs += ` color=orange`
+		}
+		fmt.Printf("  %q -> %q [%s];\n", e.From, e.To, attrs)
+	}
+	fmt.Println("}")
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long to wait for a burst of filesystem events (on any
+// watched path; one timer covers them all) to go quiet before acting on
+// them, mirroring the coalescing Hugo
+// itself does when rebuilding on changes.
+const watchDebounce = 200 * time.Millisecond
+
+// runWatch keeps the process alive, re-resolving files as they change under
+// sources, until it receives SIGINT/SIGTERM.
+func runWatch(sources []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, src := range sources {
+		if err := addWatchDirs(watcher, src); err != nil && config.Verbose {
+			fmt.Printf("⚠️ Error watching %s: %v\n", src, err)
+		}
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	var pendingMu sync.Mutex
+	pending := make(map[string]fsnotify.Op)
+	var debounce *time.Timer
+
+	flush := func() {
+		pendingMu.Lock()
+		events := pending
+		pending = make(map[string]fsnotify.Op)
+		pendingMu.Unlock()
+
+		for path, op := range events {
+			handleWatchEvent(watcher, path, op, sources)
+		}
+	}
+
+	if config.Verbose {
+		fmt.Println("👀 Watching for changes (Ctrl+C to stop)...")
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			pendingMu.Lock()
+			pending[event.Name] |= event.Op
+			if debounce != nil {
+				debounce.Stop()
+			}
+			debounce = time.AfterFunc(watchDebounce, flush)
+			pendingMu.Unlock()
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			if config.Verbose {
+				fmt.Printf("⚠️ Watcher error: %v\n", err)
+			}
+
+		case <-sigCh:
+			if config.Verbose {
+				fmt.Println("\n🛑 Shutting down watch mode")
+			}
+			if err := saveManifest(snapshotManifest()); err != nil && config.Verbose {
+				fmt.Printf("⚠️ Failed to save link cache: %v\n", err)
+			}
+			return nil
+		}
+	}
+}
+
+// addWatchDirs recursively registers dir and its non-excluded
+// subdirectories with watcher.
+func addWatchDirs(watcher *fsnotify.Watcher, dir string) error {
+	return symbolicWalk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if excludeDirs[info.Name()] {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// handleWatchEvent reacts to a coalesced filesystem event for path,
+// updating the link index and cache and re-resolving affected files.
+func handleWatchEvent(watcher *fsnotify.Watcher, path string, op fsnotify.Op, sources []string) {
+	info, statErr := os.Stat(path)
+	exists := statErr == nil
+
+	if !exists {
+		if op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+			removeFromIndex(path, sources)
+		}
+		return
+	}
+
+	if info.IsDir() {
+		if op&fsnotify.Create != 0 && !excludeDirs[info.Name()] {
+			if err := addWatchDirs(watcher, path); err != nil && config.Verbose {
+				fmt.Printf("⚠️ Error watching new directory %s: %v\n", path, err)
+			}
+		}
+		return
+	}
+
+	if !hasValidExtension(info.Name(), config.Extensions) {
+		return
+	}
+
+	if op&fsnotify.Create != 0 {
+		addToIndex(path, sources)
+	}
+
+	if op&(fsnotify.Write|fsnotify.Create) != 0 {
+		reprocessWithDependents(path, sources)
+	}
+}
+
+// addToIndex indexes a newly created file and invalidates any cached link
+// resolutions that might now resolve differently.
+func addToIndex(path string, sources []string) {
+	source, ok := sourceForPath(path, sources)
+	if !ok {
+		return
+	}
+
+	if err := indexFile(source, path); err != nil {
+		if config.Verbose {
+			fmt.Printf("⚠️ Error indexing %s: %v\n", path, err)
+		}
+		return
+	}
+
+	if _, slug, cleanPath, err := indexKeysFor(source, path); err == nil {
+		invalidateLinkCache(slug, cleanPath)
+	}
+
+	if config.Verbose {
+		fmt.Printf("➕ Indexed new file %s\n", path)
+	}
+}
+
+// removeFromIndex drops a deleted or renamed-away file from the index,
+// cache, and manifest.
+func removeFromIndex(path string, sources []string) {
+	source, ok := sourceForPath(path, sources)
+	if !ok {
+		return
+	}
+
+	if _, slug, cleanPath, err := indexKeysFor(source, path); err == nil {
+		invalidateLinkCache(slug, cleanPath)
+	}
+
+	if err := deindexFile(source, path); err != nil && config.Verbose {
+		fmt.Printf("⚠️ Error removing %s from index: %v\n", path, err)
+	}
+
+	manifestMu.Lock()
+	delete(manifest, path)
+	manifestMu.Unlock()
+
+	if config.Verbose {
+		fmt.Printf("➖ Removed %s from index\n", path)
+	}
+}
+
+// invalidateLinkCache drops cached resolutions whose target matches one of
+// the given index keys, so the next resolution picks up the index change.
+func invalidateLinkCache(keys ...string) {
+	targets := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		targets[strings.ToLower(k)] = true
+	}
+
+	linkCache.Range(func(k, v interface{}) bool {
+		cacheKey := k.(string)
+		linkName := cacheKey
+		if idx := strings.Index(cacheKey, "|"); idx >= 0 {
+			linkName = cacheKey[:idx]
+		}
+
+		if targets[strings.ToLower(linkName)] || targets[slugify(linkName)] {
+			linkCache.Delete(k)
+		}
+		return true
+	})
+}
+
+// reprocessWithDependents re-runs processFile for path and for every file
+// whose cached deps include it, mirroring how the file would be re-resolved
+// on a fresh incremental run.
+func reprocessWithDependents(path string, sources []string) {
+	if _, err := processAndRecord(path, sources); err != nil {
+		return
+	}
+
+	manifestMu.Lock()
+	var dependents []string
+	for p, e := range manifest {
+		if p == path {
+			continue
+		}
+		for _, d := range e.Deps {
+			if d == path {
+				dependents = append(dependents, p)
+				break
+			}
+		}
+	}
+	manifestMu.Unlock()
+
+	for _, dep := range dependents {
+		if _, err := os.Stat(dep); err == nil {
+			processAndRecord(dep, sources)
+		}
+	}
+}
+
+// processAndRecord processes path and, on success, records its cache entry
+// and reports the outcome the same way the initial run does.
+func processAndRecord(path string, sources []string) (CacheEntry, error) {
+	entry, err := processFile(path, sources)
+	if err != nil {
+		if config.Verbose {
+			fmt.Printf("❌ %s: %v\n", path, err)
+		}
+		return CacheEntry{}, err
+	}
+
+	manifestMu.Lock()
+	manifest[path] = entry
+	manifestMu.Unlock()
+
+	if err := saveManifest(snapshotManifest()); err != nil && config.Verbose {
+		fmt.Printf("⚠️ Failed to save link cache: %v\n", err)
+	}
+
+	if config.Verbose {
+		fmt.Printf("✅ %s re-processed\n", path)
+	}
+
+	return entry, nil
+}